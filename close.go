@@ -0,0 +1,146 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+//lint:file-ignore ST1005 - Allow any error text
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// `ErrPoolDrainTimeout` is wrapped into the error returned by
+// `CloseContext()` when `aContext` expires before all checked-out
+// resources were `Put()` back; the wrapping message carries the
+// number of resources that never returned.
+var ErrPoolDrainTimeout TPoolErr = errors.New("Timed out waiting for checked-out resources to be returned.")
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `Close` shuts down the pool and closes all existing (idle)
+// resources immediately, without waiting for resources currently
+// checked out by callers to be `Put()` back.
+//
+// It's a thin wrapper around `CloseContext()` using an already
+// expired context; use `CloseContext()` directly for a graceful,
+// waiting shutdown.
+func (pool *TResPool) Close() error {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+
+	return pool.CloseContext(ctx)
+} // Close()
+
+// `CloseContext` shuts down the pool, waiting for resources that are
+// currently checked out to be `Put()` back before closing them, up
+// to `aContext`'s deadline/cancellation.
+//
+// Once `aContext` ends, whatever is still idle in the pool is closed
+// right away; if resources are still outstanding at that point the
+// returned error wraps `ErrPoolDrainTimeout` together with how many.
+//
+//	`aContext` Bounds how long to wait for outstanding resources.
+func (pool *TResPool) CloseContext(aContext context.Context) error {
+	pool.emit(EvtClose, nil)
+
+	// Sync this operation with the Get/Put operation.
+	pool.mtx.Lock()
+
+	// If the pool is already close, don't do anything more.
+	if pool.closed {
+		pool.mtx.Unlock()
+		if DEBUG {
+			log.Println("CloseContext:", "Pool already closed")
+		}
+		// While we want to close the pool anyway we return
+		// an error to signal that the program's current logic
+		// causes the closing attempt multiple times.
+		return ErrPoolClosed
+	}
+
+	// Set the pool as closed.
+	pool.closed = true
+	if DEBUG {
+		log.Println("CloseContext:", "Closing Pool")
+	}
+
+	// Stop the reaper goroutine (if any) before we tear down the
+	// resources channel it also reads from. We must release the
+	// lock first: a reap cycle may currently be waiting on it (it
+	// bails out as soon as it sees `pool.closed`), and we'd
+	// otherwise deadlock waiting for it to finish.
+	if nil != pool.done {
+		close(pool.done)
+		pool.mtx.Unlock()
+		<-pool.reaperDone
+		pool.mtx.Lock()
+	}
+
+	// Release any callers still blocked in `Get()`'s wait queue;
+	// they'll see the channel closed and return `ErrPoolClosed`.
+	for _, w := range pool.waiters {
+		close(w)
+	}
+	pool.waiters = nil
+
+	// Wait for checked-out resources to be returned, up to aContext's
+	// deadline/cancellation.
+	pool.drainUntil(aContext)
+
+	// Close the channel before we drain it of its resources.
+	// If we don't do this, we will get a deadlock.
+	close(pool.resources)
+
+	// Close the (still idle) resources …
+	var err error
+	for entry := range pool.resources {
+		if e2 := entry.res.Close(); nil == err {
+			//TODO: wrap the error(s)
+			err = e2
+		}
+		pool.open--
+	}
+
+	if 0 < pool.outstanding {
+		if DEBUG {
+			log.Println("CloseContext:", "Never returned:", pool.outstanding)
+		}
+		err = errors.Join(err, fmt.Errorf("%w: %d", ErrPoolDrainTimeout, pool.outstanding))
+	}
+
+	pool.mtx.Unlock()
+	return err
+} // CloseContext()
+
+// `drainUntil` blocks until no resources are checked out anymore or
+// `aContext` ends, whichever comes first.
+//
+// It's called with `pool.mtx` held and always returns with it held
+// again.
+func (pool *TResPool) drainUntil(aContext context.Context) {
+	for 0 < pool.outstanding {
+		sig := pool.drainSignal
+		pool.mtx.Unlock()
+
+		select {
+		case <-sig:
+			// Some resource was returned (or discarded); go on
+			// and re-check `pool.outstanding`.
+			pool.mtx.Lock()
+
+		case <-aContext.Done():
+			pool.mtx.Lock()
+			return
+		} // select
+	} // for
+} // drainUntil()
+
+/* _EoF_ */
@@ -0,0 +1,126 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+func TestTResPool_CloseContext_waits(t *testing.T) {
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+
+	ctx := context.Background()
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		pool.Put(ctx, r)
+	}()
+
+	closeCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	if err := pool.CloseContext(closeCtx); nil != err {
+		t.Errorf("CloseContext() error = `%v`, want `%v`", err, nil)
+	}
+} // TestTResPool_CloseContext_waits()
+
+func TestTResPool_CloseContext_timesOut(t *testing.T) {
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+
+	ctx := context.Background()
+	if _, err := pool.Get(ctx); nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	err = pool.CloseContext(closeCtx)
+	if nil == err {
+		t.Fatal("CloseContext() error = `nil`, want `ErrPoolDrainTimeout`")
+	}
+	if !errors.Is(err, ErrPoolDrainTimeout) {
+		t.Errorf("CloseContext() error = `%v`, want it to wrap `%v`", err, ErrPoolDrainTimeout)
+	}
+} // TestTResPool_CloseContext_timesOut()
+
+func TestTResPool_Get_DuringDrainReturnsClosed(t *testing.T) {
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+
+	ctx := context.Background()
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+
+	closeDone := make(chan struct{})
+	go func() {
+		defer close(closeDone)
+		closeCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		pool.CloseContext(closeCtx)
+	}()
+
+	// Give `CloseContext()` time to set `pool.closed` and move past the
+	// reaper-join/waiter-release step into `drainUntil()`, where it's
+	// still blocked on `r` being checked out.
+	time.Sleep(20 * time.Millisecond)
+
+	type result struct {
+		r   io.Closer
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		got, err := pool.Get(ctx)
+		done <- result{got, err}
+	}()
+
+	select {
+	case res := <-done:
+		if !errors.Is(res.err, ErrPoolClosed) {
+			t.Errorf("Get() error = `%v`, want `%v`", res.err, ErrPoolClosed)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("Get() neither returned nor errored while the pool was draining")
+	}
+
+	pool.Put(ctx, r)
+	<-closeDone
+} // TestTResPool_Get_DuringDrainReturnsClosed()
+
+func TestTResPool_Close_immediate(t *testing.T) {
+	pool, err := New(testFactory, 1, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+
+	if err := pool.Close(); nil != err {
+		t.Errorf("Close() error = `%v`, want `%v`", err, nil)
+	}
+} // TestTResPool_Close_immediate()
+
+/* _EoF_ */
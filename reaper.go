@@ -0,0 +1,161 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+//lint:file-ignore ST1005 - Allow any error text
+
+import (
+	"log"
+	"time"
+)
+
+// `Options` bundles the optional settings accepted by `NewWithOptions()`.
+//
+// The zero value disables both the reaper and resource validation,
+// making `NewWithOptions()` behave exactly like `New()`.
+type Options struct {
+	// `IdleTimeout` is the maximum time a resource may sit unused in
+	// the pool before the reaper closes it. Zero disables reaping.
+	IdleTimeout time.Duration
+
+	// `ReapInterval` is how often the reaper wakes up to look for
+	// idle resources. Zero disables reaping.
+	ReapInterval time.Duration
+
+	// `Validate`, if set, is called on a pooled resource before `Get()`
+	// hands it out; see `TValidateFunc` for details.
+	Validate TValidateFunc
+
+	// `MaxOpen` bounds the number of live resources (idle plus
+	// checked out). Zero means unbounded. Only enforced when `Mode`
+	// is `ModeWaitOrFail` or `ModeWaitOrTimeout`.
+	MaxOpen int
+
+	// `Mode` selects what `Get()` does once `MaxOpen` is reached and
+	// no idle resource is available; see the `Mode` constants.
+	Mode Mode
+
+	// `WaitTimeout` bounds how long a `Get()` call may wait for a
+	// free resource when `Mode` is `ModeWaitOrTimeout`.
+	WaitTimeout time.Duration
+
+	// `OnEvent`, if set, is called for notable occurrences (see the
+	// `Evt…` constants) so callers can feed a Prometheus/OpenTelemetry
+	// exporter without this package depending on either. It's called
+	// synchronously from the triggering call, so it must return
+	// quickly and must not call back into the pool.
+	OnEvent TOnEventFunc
+}
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `NewWithOptions` creates a pool like `New()` does but additionally
+// accepts `aOpts` to configure optional behaviour.
+//
+// When both `aOpts.IdleTimeout` and `aOpts.ReapInterval` are non-zero
+// a background goroutine ("the reaper") wakes up every `ReapInterval`
+// and closes resources that have been sitting idle in the pool longer
+// than `IdleTimeout`. This lets long-running services release stale
+// DB/network connections without waiting for the next `Get()`.
+//
+//	`aFunc` A user provided function that can allocate a new resource.
+//	`aLen` The number of elements to initialise at startup.
+//	`aCap` Tha maximal number of elements in the pool.
+//	`aOpts` Optional settings (e.g. idle timeout/reap interval).
+func NewWithOptions(aFunc TCreateFunc, aLen, aCap int, aOpts Options) (*TResPool, TPoolErr) {
+	rPool, err := newPool(aFunc, aLen, aCap)
+	if nil != err {
+		return nil, err
+	}
+
+	rPool.validate = aOpts.Validate
+	rPool.maxOpen = aOpts.MaxOpen
+	rPool.mode = aOpts.Mode
+	rPool.waitTimeout = aOpts.WaitTimeout
+	rPool.onEvent = aOpts.OnEvent
+
+	if (0 < aOpts.IdleTimeout) && (0 < aOpts.ReapInterval) {
+		rPool.idleTimeout = aOpts.IdleTimeout
+		rPool.reapInterval = aOpts.ReapInterval
+		rPool.done = make(chan struct{})
+		rPool.reaperDone = make(chan struct{})
+
+		go rPool.reapLoop()
+	}
+
+	return rPool, nil
+} // NewWithOptions()
+
+// `reapLoop` periodically calls `reapIdle()` until the pool is closed.
+func (pool *TResPool) reapLoop() {
+	ticker := time.NewTicker(pool.reapInterval)
+	defer ticker.Stop()
+	defer close(pool.reaperDone)
+
+	for {
+		select {
+		case <-pool.done:
+			if DEBUG {
+				log.Println("reapLoop:", "Stopping")
+			}
+			return
+
+		case <-ticker.C:
+			pool.reapIdle()
+		}
+	}
+} // reapLoop()
+
+// `reapIdle` closes and drops resources that have been idle in the
+// pool longer than `pool.idleTimeout`, putting the still-fresh ones
+// back.
+func (pool *TResPool) reapIdle() {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	if pool.closed {
+		return
+	}
+
+	// Only look at the resources that were already waiting when we
+	// started; anything `Put()` back while we're running is left
+	// alone for the next run.
+	for n := len(pool.resources); 0 < n; n-- {
+		select {
+		case entry, ok := <-pool.resources:
+			if !ok {
+				return
+			}
+
+			if time.Since(entry.returnedAt) > pool.idleTimeout {
+				entry.res.Close()
+				pool.open--
+				if DEBUG {
+					log.Println("reapIdle:", "Closed idle resource")
+				}
+				continue
+			}
+
+			select {
+			case pool.resources <- entry:
+				// Still fresh: keep it.
+			default:
+				// Should never happen since we only ever
+				// remove entries in this loop, but don't
+				// leak the resource if it does.
+				entry.res.Close()
+				pool.open--
+			}
+
+		default:
+			return
+		}
+	}
+} // reapIdle()
+
+/* _EoF_ */
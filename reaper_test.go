@@ -0,0 +1,53 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"testing"
+	"time"
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+func TestNewWithOptions(t *testing.T) {
+	DEBUG = true
+
+	pool, err := NewWithOptions(testFactory, 2, 4, Options{
+		IdleTimeout:  10 * time.Millisecond,
+		ReapInterval: 5 * time.Millisecond,
+	})
+	if nil != err {
+		t.Fatalf("NewWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	if got := pool.Len(); 2 != got {
+		t.Errorf("NewWithOptions() Len() = `%v`, want `%v`", got, 2)
+	}
+
+	// Give the reaper a few cycles to run; the freshly initialised
+	// resources should be closed once they exceed `IdleTimeout`.
+	time.Sleep(50 * time.Millisecond)
+
+	if got := pool.Len(); 0 != got {
+		t.Errorf("NewWithOptions() Len() after reap = `%v`, want `%v`", got, 0)
+	}
+} // TestNewWithOptions()
+
+func TestNewWithOptions_zero(t *testing.T) {
+	pool, err := NewWithOptions(testFactory, 1, 2, Options{})
+	if nil != err {
+		t.Fatalf("NewWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	if nil != pool.done {
+		t.Error("NewWithOptions() started a reaper despite zero Options")
+	}
+} // TestNewWithOptions_zero()
+
+/* _EoF_ */
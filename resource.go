@@ -0,0 +1,133 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+//lint:file-ignore ST1005 - Allow any error text
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// `Resource` wraps a resource checked out of a `TResPool`, returning it
+// to the pool automatically when `Close()` is called. It lets callers
+// write `r, err := pool.GetResource(ctx); defer r.Close()` instead of
+// having to remember a matching `pool.Put()`.
+//
+// Use `GetResource()` to obtain one; don't construct a `Resource`
+// directly.
+type Resource struct {
+	io.Closer
+	pool     *TResPool
+	ctx      context.Context
+	mtx      sync.Mutex
+	returned bool
+}
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `GetResource` is like `Get()` but wraps the checked-out resource in a
+// `Resource`, so callers can `defer r.Close()` to have it returned to
+// the pool automatically.
+//
+//	`aContext` A (possibly canceled) context.
+func (pool *TResPool) GetResource(aContext context.Context) (*Resource, error) {
+	r, err := pool.Get(aContext)
+	if nil != err {
+		return nil, err
+	}
+
+	return &Resource{
+		Closer: r,
+		pool:   pool,
+		ctx:    aContext,
+	}, nil
+} // GetResource()
+
+// `Close` returns the wrapped resource to the pool it came from. It's
+// idempotent: only the first call has any effect, so `defer r.Close()`
+// is safe even if the resource was already `Destroy()`ed or `Close()`d
+// earlier.
+func (r *Resource) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.returned {
+		return nil
+	}
+	r.returned = true
+
+	return r.pool.Put(r.ctx, r.Closer)
+} // Close()
+
+// `Destroy` marks the wrapped resource as broken: instead of being
+// returned to the pool it's closed right away and dropped, like a
+// resource that failed validation. Like `Close()` it's idempotent.
+func (r *Resource) Destroy() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.returned {
+		return nil
+	}
+	r.returned = true
+
+	return r.pool.discard(r.Closer)
+} // Destroy()
+
+// `discard` closes `aResource` instead of returning it to the pool,
+// doing the same accounting `Put()` does when it kills a resource
+// (`open`/`outstanding` counts, `drainSignal`, stats and `OnEvent`).
+func (pool *TResPool) discard(aResource io.Closer) error {
+	pool.mtx.Lock()
+	pool.outstanding--
+	close(pool.drainSignal)
+	pool.drainSignal = make(chan struct{})
+	err := aResource.Close()
+	pool.open--
+	pool.mtx.Unlock()
+
+	pool.stats.discards.Add(1)
+	pool.emit(EvtDiscard, err)
+
+	return err
+} // discard()
+
+// `WithResource` acquires a resource, passes it to `aFunc`, and always
+// returns it to the pool afterwards - on normal return, on an error
+// from `aFunc`, and even if `aFunc` panics (the resource is `Put()`
+// back before the panic is re-raised).
+//
+// It saves callers from having to pair every `Get()` with a `Put()` by
+// hand.
+//
+//	`aContext` A (possibly canceled) context.
+//	`aFunc` The function to run with the checked-out resource.
+func (pool *TResPool) WithResource(aContext context.Context, aFunc func(io.Closer) error) (rErr error) {
+	r, err := pool.Get(aContext)
+	if nil != err {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); nil != p {
+			pool.Put(aContext, r)
+			panic(p)
+		}
+	}()
+
+	rErr = aFunc(r)
+	if pErr := pool.Put(aContext, r); nil == rErr {
+		rErr = pErr
+	}
+
+	return
+} // WithResource()
+
+/* _EoF_ */
@@ -0,0 +1,124 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+func TestTResPool_GetResource(t *testing.T) {
+	ctx := context.Background()
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	r, err := pool.GetResource(ctx)
+	if nil != err {
+		t.Fatalf("GetResource() error = `%v`", err)
+	}
+	if 1 != pool.Stats().InUse {
+		t.Fatalf("Stats().InUse = `%v`, want `%v`", pool.Stats().InUse, 1)
+	}
+
+	if err := r.Close(); nil != err {
+		t.Errorf("Resource.Close() error = `%v`", err)
+	}
+	if 0 != pool.Stats().InUse {
+		t.Errorf("Stats().InUse = `%v`, want `%v`", pool.Stats().InUse, 0)
+	}
+	if 1 != pool.Len() {
+		t.Errorf("Len() = `%v`, want `%v`", pool.Len(), 1)
+	}
+
+	// Calling `Close()` again must be a no-op.
+	if err := r.Close(); nil != err {
+		t.Errorf("Resource.Close() (2) error = `%v`", err)
+	}
+} // TestTResPool_GetResource()
+
+func TestTResPool_Resource_Destroy(t *testing.T) {
+	ctx := context.Background()
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	r, err := pool.GetResource(ctx)
+	if nil != err {
+		t.Fatalf("GetResource() error = `%v`", err)
+	}
+
+	if err := r.Destroy(); nil != err {
+		t.Errorf("Resource.Destroy() error = `%v`", err)
+	}
+	if 0 != pool.Len() {
+		t.Errorf("Len() = `%v`, want `%v`", pool.Len(), 0)
+	}
+	if 0 != pool.Open() {
+		t.Errorf("Open() = `%v`, want `%v`", pool.Open(), 0)
+	}
+
+	// `Close()` after `Destroy()` must be a no-op, not a double Put.
+	if err := r.Close(); nil != err {
+		t.Errorf("Resource.Close() error = `%v`", err)
+	}
+	if 0 != pool.Len() {
+		t.Errorf("Len() = `%v`, want `%v`", pool.Len(), 0)
+	}
+} // TestTResPool_Resource_Destroy()
+
+func TestTResPool_WithResource(t *testing.T) {
+	ctx := context.Background()
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	wantErr := errors.New("boom")
+	err = pool.WithResource(ctx, func(io.Closer) error {
+		return wantErr
+	})
+	if wantErr != err {
+		t.Errorf("WithResource() error = `%v`, want `%v`", err, wantErr)
+	}
+	if 1 != pool.Len() {
+		t.Errorf("Len() = `%v`, want `%v`", pool.Len(), 1)
+	}
+} // TestTResPool_WithResource()
+
+func TestTResPool_WithResource_panic(t *testing.T) {
+	ctx := context.Background()
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	defer func() {
+		if nil == recover() {
+			t.Error("WithResource() did not re-panic")
+		}
+		if 1 != pool.Len() {
+			t.Errorf("Len() = `%v`, want `%v`", pool.Len(), 1)
+		}
+	}()
+
+	pool.WithResource(ctx, func(io.Closer) error {
+		panic("boom")
+	})
+} // TestTResPool_WithResource_panic()
+
+/* _EoF_ */
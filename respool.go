@@ -15,6 +15,7 @@ import (
 	"io"
 	"log"
 	"sync"
+	"time"
 )
 
 type (
@@ -26,15 +27,37 @@ type (
 	// close/free/release the resource created by this function.
 	TCreateFunc func() (io.Closer, error)
 
+	// `tPooledEntry` wraps a resource sitting idle in `pool.resources`
+	// together with the time it was put there, so the reaper (see
+	// `reaper.go`) can tell how long it's been unused.
+	tPooledEntry struct {
+		res        io.Closer
+		returnedAt time.Time
+	}
+
 	// `TResPool` manages a set of resources that can be shared
 	// safely by multiple goroutines.
 	// The resource being managed must implement the `io.Closer`
 	// interface.
 	TResPool struct {
-		factory   TCreateFunc
-		mtx       sync.Mutex
-		resources chan io.Closer
-		closed    bool
+		factory      TCreateFunc
+		validate     TValidateFunc
+		mtx          sync.Mutex
+		resources    chan tPooledEntry
+		closed       bool
+		idleTimeout  time.Duration
+		reapInterval time.Duration
+		done         chan struct{}
+		reaperDone   chan struct{} // closed once `reapLoop()` has returned
+		open         int           // number of live resources, idle or checked out
+		maxOpen      int           // `0` means unbounded
+		mode         Mode
+		waitTimeout  time.Duration
+		waiters      []chan io.Closer // FIFO queue of blocked `Get()` callers
+		outstanding  int              // number of resources currently checked out
+		drainSignal  chan struct{}    // closed (and replaced) whenever `outstanding` drops
+		stats        tPoolStats       // atomic runtime counters, see `Stats()`
+		onEvent      TOnEventFunc     // optional observability hook, see `Options.OnEvent`
 	}
 
 	// `TPoolErr` is the base error for all error conditions
@@ -57,6 +80,11 @@ var (
 	// `ErrPoolInit` is returned if `New()` has problems initialising
 	// the first `aLen` pool items.
 	ErrPoolInit TPoolErr = errors.New("Can't init Len pool elements.")
+
+	// `ErrPoolTimeout` is returned by `Get()` when a caller has been
+	// waiting for a free resource (see `Options.MaxOpen`/`Mode`) and
+	// the per-call wait deadline (`Options.WaitTimeout`) expires.
+	ErrPoolTimeout TPoolErr = errors.New("Timeout waiting for a free resource.")
 )
 
 // `DEBUG` activates some screen output (if set `true`);
@@ -77,80 +105,99 @@ func (pool *TResPool) Cap() (rCap int) {
 	return
 } // Cap()
 
-// `Close` will shutdown the pool and close all existing resources.
-func (pool *TResPool) Close() error {
-	// We don't expect a `context` here because we have
-	// to close/free our resources in any case.
-
-	// Sync this operation with the Get/Put operation.
-	pool.mtx.Lock()
-	defer pool.mtx.Unlock()
-
-	// If the pool is already close, don't do anything more.
-	if pool.closed {
-		if DEBUG {
-			log.Println("Close:", "Pool already closed")
-		}
-		// While we want to close the pool anyway we return
-		// an error to signal that the program's current logic
-		// causes the closing attempt multiple times.
-		return ErrPoolClosed
-	}
-
-	// Set the pool as closed.
-	pool.closed = true
-	if DEBUG {
-		log.Println("Close:", "Closing Pool")
-	}
-
-	// Close the channel before we drain it of its resources.
-	// If we don't do this, we will get a deadlock.
-	close(pool.resources)
-
-	// Close the resources …
-	var err error
-	for r := range pool.resources {
-		if e2 := r.Close(); nil == err {
-			//TODO: wrap the error(s)
-			err = e2
-		}
-	}
-	return err
-} // Close()
-
 // `Get` retrieves a resource from the pool.
 //
+// Resources pulled from the pool are checked with `isValid()` before
+// being handed out; a resource that fails validation is closed and
+// dropped, and the next one (or a freshly created one) is tried instead.
+//
+// If the pool has a `MaxOpen` bound (see `Options`) and it's already
+// reached, and `Mode` is `ModeWaitOrFail` or `ModeWaitOrTimeout`, the
+// call parks on a FIFO wait queue until a resource is `Put()` back,
+// `aContext` is done (`ErrPoolDone`), or - for `ModeWaitOrTimeout` -
+// the pool's `WaitTimeout` expires (`ErrPoolTimeout`).
+//
 //	`aContext` A (possibly canceled) context.
 func (pool *TResPool) Get(aContext context.Context) (io.Closer, error) {
+	pool.stats.gets.Add(1)
+	pool.emit(EvtGet, nil)
+
 	// Sync this operation with Close/Put operations.
 	pool.mtx.Lock()
-	defer pool.mtx.Unlock()
-
-	select {
-	// Check whether we're already done.
-	case <-aContext.Done():
-		if DEBUG {
-			log.Println("Get:", "Context is done.")
-		}
-		return nil, ErrPoolDone
 
-	// Check for a free resource.
-	case r, ok := <-pool.resources:
+	// A `CloseContext()` in progress may release `mtx` for a while
+	// (e.g. to join the reaper goroutine, or while draining) before
+	// it closes `pool.resources`; check the flag directly so we don't
+	// hand out a resource, mint a new one, or park as a wait-queue
+	// entry nobody will ever release.
+	if pool.closed {
+		pool.mtx.Unlock()
 		if DEBUG {
-			log.Println("Get:", "Shared Resource -- ", ok)
-		}
-		if ok {
-			return r, nil
+			log.Println("Get:", "Pool is closed")
 		}
 		return nil, ErrPoolClosed
+	}
 
-	// Provide a new resource since there are none available.
-	default:
-		if DEBUG {
-			log.Println("Get:", "New Resource")
-		}
-		return pool.factory()
-	} // select
+	for {
+		select {
+		// Check whether we're already done.
+		case <-aContext.Done():
+			pool.mtx.Unlock()
+			if DEBUG {
+				log.Println("Get:", "Context is done.")
+			}
+			return nil, ErrPoolDone
+
+		// Check for a free resource.
+		case entry, ok := <-pool.resources:
+			if DEBUG {
+				log.Println("Get:", "Shared Resource -- ", ok)
+			}
+			if !ok {
+				pool.mtx.Unlock()
+				return nil, ErrPoolClosed
+			}
+			if pool.isValid(entry.res) {
+				pool.stats.hits.Add(1)
+				pool.outstanding++
+				pool.mtx.Unlock()
+				return entry.res, nil
+			}
+			entry.res.Close()
+			pool.open--
+			pool.stats.discards.Add(1)
+			pool.emit(EvtValidateFail, nil)
+			if DEBUG {
+				log.Println("Get:", "Discarded invalid Resource")
+			}
+			continue // try the next pooled item, or fall back to `factory()`
+
+		// No idle resource is available right now.
+		default:
+			if (ModeGrow == pool.mode) || (0 >= pool.maxOpen) || (pool.open < pool.maxOpen) {
+				if DEBUG {
+					log.Println("Get:", "New Resource")
+				}
+				r, err := pool.factory()
+				pool.stats.misses.Add(1)
+				pool.emit(EvtFactory, err)
+				if nil == err {
+					pool.open++
+					pool.outstanding++
+				}
+				pool.mtx.Unlock()
+				return r, err
+			}
+
+			// At `MaxOpen`: park on the wait queue. `wait()`
+			// takes over the lock we're still holding and
+			// always returns unlocked.
+			if DEBUG {
+				log.Println("Get:", "Waiting for a free Resource")
+			}
+			return pool.waitStats(aContext)
+		} // select
+	} // for
 } // Get()
 
 // `IsClosed` tells whether the pool is already closed.
@@ -167,6 +214,20 @@ func (pool *TResPool) IsClosed() (rClosed bool) {
 	return
 } // IsClosed()
 
+// `Open` returns the number of currently live resources, i.e. those
+// idle in the pool plus those checked out by callers.
+func (pool *TResPool) Open() (rOpen int) {
+	// Sync this operation with the Get/Put operations.
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	rOpen = pool.open
+	if DEBUG {
+		log.Println("Open:", rOpen)
+	}
+	return
+} // Open()
+
 // `Len` returns the number of currently unused elements in
 // the resources pool.
 func (pool *TResPool) Len() (rLen int) {
@@ -186,21 +247,54 @@ func (pool *TResPool) Len() (rLen int) {
 //	`aContext` A (possibly canceled) context.
 //	`aResource` The resource to put back into the pool.
 func (pool *TResPool) Put(aContext context.Context, aResource io.Closer) error {
+	pool.stats.puts.Add(1)
+
 	// Sync this operation with the Close/Get operation.
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
-	// If the pool is closed, discard the resource.
+	// If the pool is closed, discard the resource. It may still be
+	// one `CloseContext()` is waiting to drain, so account for its
+	// return before reporting the error.
 	if pool.closed {
 		if DEBUG {
 			log.Println("Put:", "Queue already closed")
 		}
+		pool.outstanding--
+		close(pool.drainSignal)
+		pool.drainSignal = make(chan struct{})
+		aResource.Close()
+		pool.open--
+		pool.stats.discards.Add(1)
+		pool.emit(EvtDiscard, nil)
 		return ErrPoolClosed
 	}
 
+	// If callers are blocked in `Get()`, hand the resource directly
+	// to the longest-waiting one instead of going through the queue.
+	// It stays checked out throughout, so `outstanding` is unaffected.
+	if 0 < len(pool.waiters) {
+		w := pool.waiters[0]
+		pool.waiters = pool.waiters[1:]
+		w <- aResource // buffered with capacity 1, never blocks
+		if DEBUG {
+			log.Println("Put:", "Handed to waiter")
+		}
+		pool.emit(EvtPut, nil)
+		return nil
+	}
+
+	// From here on `aResource` is no longer checked out, one way or
+	// another; wake up anyone in `CloseContext()` waiting to drain.
+	pool.outstanding--
+	close(pool.drainSignal)
+	pool.drainSignal = make(chan struct{})
+
 	// Flag for closing the very first resource.
 	killedOldest := false
 
+	entry := tPooledEntry{res: aResource, returnedAt: time.Now()}
+
 	select {
 	// Check whether we're already done.
 	case <-aContext.Done():
@@ -210,10 +304,11 @@ func (pool *TResPool) Put(aContext context.Context, aResource io.Closer) error {
 		return ErrPoolDone
 
 	// Try to place the resource on the queue.
-	case pool.resources <- aResource:
+	case pool.resources <- entry:
 		if DEBUG {
 			log.Println("Put:", "Into Queue (I)")
 		}
+		pool.emit(EvtPut, nil)
 		return nil
 
 	// If the queue is already at capacity we close a resource.
@@ -228,11 +323,14 @@ func (pool *TResPool) Put(aContext context.Context, aResource io.Closer) error {
 			}
 			return ErrPoolDone
 
-		case res, ok := <-pool.resources:
+		case old, ok := <-pool.resources:
 			// Get the first/oldest pool element.
 			if ok {
-				err := res.Close()
+				err := old.res.Close()
+				pool.open--
 				killedOldest = true
+				pool.stats.discards.Add(1)
+				pool.emit(EvtDiscard, err)
 				if DEBUG {
 					log.Println("Put:", "Closed oldest -- ", err)
 				}
@@ -252,7 +350,7 @@ func (pool *TResPool) Put(aContext context.Context, aResource io.Closer) error {
 			}
 			return ErrPoolDone
 
-		case pool.resources <- aResource:
+		case pool.resources <- entry:
 			// This time we succeeded …
 			if DEBUG {
 				log.Println("Put:", "Into Queue (II)")
@@ -260,13 +358,14 @@ func (pool *TResPool) Put(aContext context.Context, aResource io.Closer) error {
 
 		default:
 			err := aResource.Close()
-			if DEBUG {
-				log.Println("Put:", "Closed newest -- ", err)
-			}
+			pool.open--
+			pool.stats.discards.Add(1)
+			pool.emit(EvtDiscard, err)
 			return err
 		} // select
 	}
 
+	pool.emit(EvtPut, nil)
 	return nil
 } // Put()
 
@@ -280,6 +379,12 @@ func (pool *TResPool) Put(aContext context.Context, aResource io.Closer) error {
 //	`aLen` The number of elements to initialise at startup.
 //	`aCap` Tha maximal number of elements in the pool.
 func New(aFunc TCreateFunc, aLen int, aCap int) (*TResPool, TPoolErr) {
+	return NewWithOptions(aFunc, aLen, aCap, Options{})
+} // New()
+
+// `newPool` does the actual allocation/initialisation work shared by
+// `New()` and `NewWithOptions()`.
+func newPool(aFunc TCreateFunc, aLen, aCap int) (*TResPool, TPoolErr) {
 	if 0 >= aCap {
 		if DEBUG {
 			log.Println("New:", "Invalid pool capacity:", aCap)
@@ -294,8 +399,9 @@ func New(aFunc TCreateFunc, aLen int, aCap int) (*TResPool, TPoolErr) {
 	}
 
 	rPool := TResPool{
-		factory:   aFunc,
-		resources: make(chan io.Closer, aCap),
+		factory:     aFunc,
+		resources:   make(chan tPooledEntry, aCap),
+		drainSignal: make(chan struct{}),
 	}
 
 	if 0 < aLen {
@@ -307,8 +413,9 @@ func New(aFunc TCreateFunc, aLen int, aCap int) (*TResPool, TPoolErr) {
 			if r, err := aFunc(); nil == err {
 				select {
 				// Try placing a new resource in the queue.
-				case rPool.resources <- r:
+				case rPool.resources <- tPooledEntry{res: r, returnedAt: time.Now()}:
 					// Success: go on with the loop.
+					rPool.open++
 					continue
 
 				// If the queue is already at cap we close the resource.
@@ -322,6 +429,6 @@ func New(aFunc TCreateFunc, aLen int, aCap int) (*TResPool, TPoolErr) {
 	}
 
 	return &rPool, nil
-} // New()
+} // newPool()
 
 /* _EoF_ */
@@ -0,0 +1,118 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"sync/atomic"
+)
+
+type (
+	// `EventKind` identifies the kind of occurrence passed to an
+	// `OnEvent` callback (see `Options.OnEvent`).
+	EventKind int
+
+	// `EventData` carries the details of an event reported via
+	// `OnEvent`. `Err` can be non-`nil` only for `EvtFactory` (the
+	// factory function's error) and `EvtDiscard` (the error of closing
+	// the resource); it's always `nil` for all other kinds, including
+	// `EvtValidateFail`.
+	EventData struct {
+		Err error
+	}
+
+	// `TOnEventFunc` is the callback type accepted by
+	// `Options.OnEvent`; see its docs for when it's called.
+	TOnEventFunc func(EventKind, EventData)
+
+	// `Stats` is a snapshot of a pool's runtime counters, as returned
+	// by `Stats()`.
+	Stats struct {
+		Gets           int64 // number of `Get()` calls
+		Puts           int64 // number of `Put()` calls
+		Hits           int64 // `Get()` calls served from an idle resource
+		Misses         int64 // `Get()` calls that had to call the factory
+		Discards       int64 // resources closed instead of pooled (invalid, oldest/newest killed on `Put()`)
+		WaitCount      int64 // `Get()` calls that had to park on the wait queue
+		WaitDurationNs int64 // total nanoseconds spent waiting across all `WaitCount` calls
+		Timeouts       int64 // waiting `Get()` calls that gave up with `ErrPoolTimeout`
+		InUse          int   // resources currently checked out
+		Idle           int   // resources currently idle in the pool
+		MaxOpen        int   // the pool's configured `Options.MaxOpen`
+	}
+
+	// `tPoolStats` bundles the atomically updated counters embedded in
+	// `TResPool`; kept as its own type so `TResPool`'s field list stays
+	// readable.
+	tPoolStats struct {
+		gets           atomic.Int64
+		puts           atomic.Int64
+		hits           atomic.Int64
+		misses         atomic.Int64
+		discards       atomic.Int64
+		waitCount      atomic.Int64
+		waitDurationNs atomic.Int64
+		timeouts       atomic.Int64
+	}
+)
+
+const (
+	// `EvtGet` fires on every `Get()` call, before the outcome is known.
+	EvtGet EventKind = iota
+
+	// `EvtPut` fires on every `Put()` call that successfully returns
+	// (or hands off) a resource.
+	EvtPut
+
+	// `EvtFactory` fires whenever `Get()` calls the factory function to
+	// create a new resource. The resources created at construction
+	// time (`aLen`) don't fire it.
+	EvtFactory
+
+	// `EvtClose` fires once per `CloseContext()` call.
+	EvtClose
+
+	// `EvtDiscard` fires whenever a resource is closed instead of
+	// being kept in the pool (oldest/newest killed on `Put()`, `Put()`
+	// into a closed pool, `Resource.Destroy()`). A resource that fails
+	// validation on `Get()` fires `EvtValidateFail` instead.
+	EvtDiscard
+
+	// `EvtValidateFail` fires when `Validate` rejects a pooled
+	// resource.
+	EvtValidateFail
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `Stats` returns a snapshot of the pool's runtime counters.
+func (pool *TResPool) Stats() (rStats Stats) {
+	pool.mtx.Lock()
+	rStats.InUse = pool.outstanding
+	rStats.Idle = len(pool.resources)
+	rStats.MaxOpen = pool.maxOpen
+	pool.mtx.Unlock()
+
+	rStats.Gets = pool.stats.gets.Load()
+	rStats.Puts = pool.stats.puts.Load()
+	rStats.Hits = pool.stats.hits.Load()
+	rStats.Misses = pool.stats.misses.Load()
+	rStats.Discards = pool.stats.discards.Load()
+	rStats.WaitCount = pool.stats.waitCount.Load()
+	rStats.WaitDurationNs = pool.stats.waitDurationNs.Load()
+	rStats.Timeouts = pool.stats.timeouts.Load()
+
+	return
+} // Stats()
+
+// `emit` calls `pool.onEvent` (if set) with `aKind` and `aErr`.
+func (pool *TResPool) emit(aKind EventKind, aErr error) {
+	if nil != pool.onEvent {
+		pool.onEvent(aKind, EventData{Err: aErr})
+	}
+} // emit()
+
+/* _EoF_ */
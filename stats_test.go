@@ -0,0 +1,92 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"context"
+	"testing"
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+func TestTResPool_Stats(t *testing.T) {
+	ctx := context.Background()
+	pool, err := New(testFactory, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+
+	got := pool.Stats()
+	if 1 != got.Gets {
+		t.Errorf("Stats().Gets = `%v`, want `%v`", got.Gets, 1)
+	}
+	if 1 != got.Misses {
+		t.Errorf("Stats().Misses = `%v`, want `%v`", got.Misses, 1)
+	}
+	if 1 != got.InUse {
+		t.Errorf("Stats().InUse = `%v`, want `%v`", got.InUse, 1)
+	}
+
+	if err := pool.Put(ctx, r); nil != err {
+		t.Fatalf("Put() error = `%v`", err)
+	}
+	if _, err := pool.Get(ctx); nil != err {
+		t.Fatalf("Get() (2) error = `%v`", err)
+	}
+
+	got = pool.Stats()
+	if 2 != got.Gets {
+		t.Errorf("Stats().Gets = `%v`, want `%v`", got.Gets, 2)
+	}
+	if 1 != got.Puts {
+		t.Errorf("Stats().Puts = `%v`, want `%v`", got.Puts, 1)
+	}
+	if 1 != got.Hits {
+		t.Errorf("Stats().Hits = `%v`, want `%v`", got.Hits, 1)
+	}
+} // TestTResPool_Stats()
+
+func TestTResPool_Stats_OnEvent(t *testing.T) {
+	ctx := context.Background()
+	var kinds []EventKind
+
+	pool, err := NewWithOptions(testFactory, 0, 1, Options{
+		OnEvent: func(aKind EventKind, aData EventData) {
+			kinds = append(kinds, aKind)
+		},
+	})
+	if nil != err {
+		t.Fatalf("NewWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+	if err := pool.Put(ctx, r); nil != err {
+		t.Fatalf("Put() error = `%v`", err)
+	}
+
+	want := []EventKind{EvtGet, EvtFactory, EvtPut}
+	if len(want) != len(kinds) {
+		t.Fatalf("OnEvent() kinds = `%v`, want `%v`", kinds, want)
+	}
+	for i, k := range want {
+		if k != kinds[i] {
+			t.Errorf("OnEvent() kinds[%d] = `%v`, want `%v`", i, kinds[i], k)
+		}
+	}
+} // TestTResPool_Stats_OnEvent()
+
+/* _EoF_ */
@@ -0,0 +1,293 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+//lint:file-ignore ST1005 - Allow any error text
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// `tStripedResource` wraps a resource checked out of a
+	// `TStripedPool` together with the index of the stripe it came
+	// from, so `Put()` can hand it back to the very same sub-pool
+	// instead of having to guess.
+	tStripedResource struct {
+		io.Closer
+		stripe int
+	}
+
+	// `TStripedPool` partitions a logical resource pool into `N`
+	// independent sub-pools ("stripes"), each a regular `TResPool`
+	// with its own channel, mutex, and factory-created resources.
+	// Spreading the load across stripes reduces the mutex contention
+	// a single `TResPool` suffers under when many goroutines share it.
+	TStripedPool struct {
+		stripes []*TResPool
+		next    uint64 // round-robin counter, accessed atomically
+	}
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `Cap` returns the striped pool's total capacity, i.e. the sum of
+// all its stripes' capacities.
+func (sp *TStripedPool) Cap() (rCap int) {
+	for _, stripe := range sp.stripes {
+		rCap += stripe.Cap()
+	}
+	if DEBUG {
+		log.Println("TStripedPool.Cap:", rCap)
+	}
+	return
+} // Cap()
+
+// `Close` shuts down every stripe and closes its idle resources;
+// resources still checked out are not waited for (see `CloseContext()`).
+//
+// The individual stripes' errors (if any) are combined into a single
+// error using `errors.Join()`.
+func (sp *TStripedPool) Close() error {
+	var errs []error
+	for _, stripe := range sp.stripes {
+		if err := stripe.Close(); nil != err {
+			errs = append(errs, err)
+		}
+	}
+	if DEBUG {
+		log.Println("TStripedPool.Close:", "Closing", len(sp.stripes), "stripes")
+	}
+	return errors.Join(errs...)
+} // Close()
+
+// `Get` retrieves a resource from one of the pool's stripes, chosen
+// by a simple round-robin counter.
+//
+//	`aContext` A (possibly canceled) context.
+func (sp *TStripedPool) Get(aContext context.Context) (io.Closer, error) {
+	idx := int(atomic.AddUint64(&sp.next, 1) % uint64(len(sp.stripes)))
+
+	return sp.getStripe(aContext, idx)
+} // Get()
+
+// `GetFor` retrieves a resource from the stripe selected by hashing
+// `aKey`. Callers that want related work to stick to the same stripe
+// (e.g. per-connection or per-tenant affinity) should use this instead
+// of `Get()`.
+//
+//	`aContext` A (possibly canceled) context.
+//	`aKey` The key used to select the stripe.
+func (sp *TStripedPool) GetFor(aContext context.Context, aKey string) (io.Closer, error) {
+	h := fnv.New32a()
+	h.Write([]byte(aKey)) // a `Hash32` write never returns an error
+	idx := int(h.Sum32() % uint32(len(sp.stripes)))
+
+	return sp.getStripe(aContext, idx)
+} // GetFor()
+
+// `getStripe` fetches a resource from the stripe numbered `aIdx` and
+// wraps it so `Put()` can later find its way back home.
+func (sp *TStripedPool) getStripe(aContext context.Context, aIdx int) (io.Closer, error) {
+	r, err := sp.stripes[aIdx].Get(aContext)
+	if nil != err {
+		return nil, err
+	}
+	if DEBUG {
+		log.Println("TStripedPool.Get:", "Stripe", aIdx)
+	}
+
+	return &tStripedResource{Closer: r, stripe: aIdx}, nil
+} // getStripe()
+
+// `CloseContext` gracefully shuts down every stripe, waiting (up to
+// `aContext`'s deadline/cancellation) for resources currently checked
+// out of each one to be `Put()` back before closing them; see
+// `TResPool.CloseContext()`.
+//
+// The stripes are closed concurrently against the same `aContext`, so
+// the total time this call takes is bound by the slowest stripe's
+// drain, not the sum of all of them. The individual stripes' errors
+// (if any) are combined into a single error using `errors.Join()`.
+func (sp *TStripedPool) CloseContext(aContext context.Context) error {
+	errs := make([]error, len(sp.stripes))
+
+	var wg sync.WaitGroup
+	wg.Add(len(sp.stripes))
+	for i, stripe := range sp.stripes {
+		go func(aIdx int, aStripe *TResPool) {
+			defer wg.Done()
+			errs[aIdx] = aStripe.CloseContext(aContext)
+		}(i, stripe)
+	}
+	wg.Wait()
+
+	if DEBUG {
+		log.Println("TStripedPool.CloseContext:", "Closing", len(sp.stripes), "stripes")
+	}
+	return errors.Join(errs...)
+} // CloseContext()
+
+// `IsClosed` tells whether all of the pool's stripes are closed.
+func (sp *TStripedPool) IsClosed() bool {
+	for _, stripe := range sp.stripes {
+		if !stripe.IsClosed() {
+			return false
+		}
+	}
+
+	return true
+} // IsClosed()
+
+// `Len` returns the number of currently unused elements summed across
+// all of the pool's stripes.
+func (sp *TStripedPool) Len() (rLen int) {
+	for _, stripe := range sp.stripes {
+		rLen += stripe.Len()
+	}
+	if DEBUG {
+		log.Println("TStripedPool.Len:", rLen)
+	}
+	return
+} // Len()
+
+// `Open` returns the number of currently live resources, idle plus
+// checked out, summed across all of the pool's stripes.
+func (sp *TStripedPool) Open() (rOpen int) {
+	for _, stripe := range sp.stripes {
+		rOpen += stripe.Open()
+	}
+	if DEBUG {
+		log.Println("TStripedPool.Open:", rOpen)
+	}
+	return
+} // Open()
+
+// `Put` places a resource back into the stripe it was checked out
+// from.
+//
+//	`aContext` A (possibly canceled) context.
+//	`aResource` The resource to put back into the pool.
+func (sp *TStripedPool) Put(aContext context.Context, aResource io.Closer) error {
+	sr, ok := aResource.(*tStripedResource)
+	if !ok {
+		// Not one of ours (or already unwrapped): give it to the
+		// first stripe rather than rejecting it outright.
+		if DEBUG {
+			log.Println("TStripedPool.Put:", "Unrecognised resource, using stripe 0")
+		}
+		return sp.stripes[0].Put(aContext, aResource)
+	}
+
+	return sp.stripes[sr.stripe].Put(aContext, sr.Closer)
+} // Put()
+
+// `Stats` returns a snapshot of the pool's runtime counters, summed
+// across all of its stripes; see `TResPool.Stats()`.
+func (sp *TStripedPool) Stats() (rStats Stats) {
+	for _, stripe := range sp.stripes {
+		s := stripe.Stats()
+		rStats.Gets += s.Gets
+		rStats.Puts += s.Puts
+		rStats.Hits += s.Hits
+		rStats.Misses += s.Misses
+		rStats.Discards += s.Discards
+		rStats.WaitCount += s.WaitCount
+		rStats.WaitDurationNs += s.WaitDurationNs
+		rStats.Timeouts += s.Timeouts
+		rStats.InUse += s.InUse
+		rStats.Idle += s.Idle
+		rStats.MaxOpen += s.MaxOpen
+	}
+	return
+} // Stats()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `NewStriped` creates a pool that manages resources across `aStripes`
+// independent sub-pools, each a regular `TResPool`.
+//
+// `aLen` and `aCap` are distributed as evenly as possible across the
+// stripes; a remainder (if any) is given to the first stripes. `Get`
+// picks a stripe round-robin (or, via `GetFor()`, by hashing a
+// caller-supplied key) so concurrent callers mostly contend for
+// different stripes' locks instead of a single global one.
+//
+// Calling `NewStriped(aFunc, aLen, aCap, 1)` behaves exactly like
+// `New(aFunc, aLen, aCap)`, just wrapped in the striped pool's API.
+//
+//	`aFunc` A user provided function that can allocate a new resource.
+//	`aLen` The number of elements to initialise at startup.
+//	`aCap` Tha maximal number of elements in the pool.
+//	`aStripes` The number of independent sub-pools to partition into.
+func NewStriped(aFunc TCreateFunc, aLen, aCap, aStripes int) (*TStripedPool, TPoolErr) {
+	return NewStripedWithOptions(aFunc, aLen, aCap, aStripes, Options{})
+} // NewStriped()
+
+// `NewStripedWithOptions` creates a striped pool like `NewStriped()`
+// does, but additionally accepts `aOpts` to configure each stripe -
+// `Validate`, `MaxOpen`/`Mode`, the idle reaper, and `OnEvent` are all
+// applied to every stripe exactly as `NewWithOptions()` would apply
+// them to a single, unstriped pool.
+//
+// Note that `aOpts.MaxOpen`, if set, bounds each stripe individually,
+// not the striped pool as a whole; size it (and `aStripes`) with that
+// in mind.
+//
+//	`aFunc` A user provided function that can allocate a new resource.
+//	`aLen` The number of elements to initialise at startup.
+//	`aCap` Tha maximal number of elements in the pool.
+//	`aStripes` The number of independent sub-pools to partition into.
+//	`aOpts` Optional settings applied to every stripe.
+func NewStripedWithOptions(aFunc TCreateFunc, aLen, aCap, aStripes int, aOpts Options) (*TStripedPool, TPoolErr) {
+	if 0 >= aStripes {
+		aStripes = 1
+	}
+	if aCap < aStripes {
+		if DEBUG {
+			log.Println("NewStriped:", "Invalid pool capacity:", aCap, "for", aStripes, "stripes")
+		}
+		return nil, ErrPoolCapacity
+	}
+
+	baseCap, remCap := aCap/aStripes, aCap%aStripes
+	baseLen, remLen := aLen/aStripes, aLen%aStripes
+
+	stripes := make([]*TResPool, aStripes)
+	for i := 0; i < aStripes; i++ {
+		sCap, sLen := baseCap, baseLen
+		if i < remCap {
+			sCap++
+		}
+		if i < remLen {
+			sLen++
+		}
+
+		stripe, err := NewWithOptions(aFunc, sLen, sCap, aOpts)
+		if nil != err {
+			for j := 0; j < i; j++ {
+				stripes[j].Close()
+			}
+			return nil, err
+		}
+		stripes[i] = stripe
+	}
+	if DEBUG {
+		log.Println("NewStriped:", "Created", aStripes, "stripes")
+	}
+
+	return &TStripedPool{stripes: stripes}, nil
+} // NewStripedWithOptions()
+
+/* _EoF_ */
@@ -0,0 +1,216 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+func TestNewStriped(t *testing.T) {
+	type tArgs struct {
+		aFunc    TCreateFunc
+		aLen     int
+		aCap     int
+		aStripes int
+	}
+	tests := []struct {
+		name    string
+		args    tArgs
+		wantErr bool
+	}{
+		{"1", tArgs{testFactory, 0, 4, 2}, false},
+		{"2", tArgs{testFactory, 4, 8, 4}, false},
+		{"3", tArgs{testFactory, 0, 1, 4}, true},
+		{"4", tArgs{testFactory, 2, 4, 0}, false},
+		// TODO: Add test cases.
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := NewStriped(tt.args.aFunc, tt.args.aLen, tt.args.aCap, tt.args.aStripes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewStriped() error = `%v`, wantErr `%v`", err, tt.wantErr)
+				return
+			}
+			if nil != pool {
+				pool.Close()
+			}
+		})
+	}
+} // TestNewStriped()
+
+func TestTStripedPool_GetPut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool, err := NewStriped(testFactory, 2, 4, 2)
+	if nil != err {
+		t.Fatalf("NewStriped() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	if got := pool.Cap(); 4 != got {
+		t.Errorf("TStripedPool.Cap() = `%v`, want `%v`", got, 4)
+	}
+
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("TStripedPool.Get() error = `%v`", err)
+	}
+	if err := pool.Put(ctx, r); nil != err {
+		t.Errorf("TStripedPool.Put() error = `%v`", err)
+	}
+
+	r2, err := pool.GetFor(ctx, "some-key")
+	if nil != err {
+		t.Fatalf("TStripedPool.GetFor() error = `%v`", err)
+	}
+	if err := pool.Put(ctx, r2); nil != err {
+		t.Errorf("TStripedPool.Put() error = `%v`", err)
+	}
+} // TestTStripedPool_GetPut()
+
+func TestNewStripedWithOptions(t *testing.T) {
+	calls := 0
+	factory := func() (io.Closer, error) {
+		calls++
+		return &poolableCloser{open: true}, nil
+	}
+
+	pool, err := NewStripedWithOptions(factory, 0, 4, 2, Options{
+		Validate: func(r io.Closer) bool {
+			return r.(*poolableCloser).open
+		},
+	})
+	if nil != err {
+		t.Fatalf("NewStripedWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	dead := &poolableCloser{open: false}
+	if err := pool.Put(ctx, dead); nil != err {
+		t.Fatalf("Put() error = `%v`", err)
+	}
+
+	// `Validate` must have been threaded through to (at least) the
+	// stripe `dead` landed in (stripe 0, as it isn't a wrapped
+	// resource), so `Get()` discards it instead of handing it back out.
+	// `Get()` picks stripes round-robin, so two calls reach both.
+	for i := 0; i < 2; i++ {
+		r, err := pool.Get(ctx)
+		if nil != err {
+			t.Fatalf("Get() error = `%v`", err)
+		}
+		if c, ok := r.(*tStripedResource); ok {
+			if p, ok := c.Closer.(*poolableCloser); ok && !p.open {
+				t.Error("Get() returned the closed resource, Validate wasn't applied")
+			}
+		}
+	}
+} // TestNewStripedWithOptions()
+
+func TestTStripedPool_Open(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewStriped(testFactory, 0, 4, 2)
+	if nil != err {
+		t.Fatalf("NewStriped() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	if got := pool.Open(); 0 != got {
+		t.Errorf("TStripedPool.Open() = `%v`, want `%v`", got, 0)
+	}
+
+	// No idle resources were preallocated, so `Get()` must create one,
+	// bumping the owning stripe's (and thus the total's) `Open()`.
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+	if got := pool.Open(); 1 != got {
+		t.Errorf("TStripedPool.Open() = `%v`, want `%v`", got, 1)
+	}
+	pool.Put(ctx, r)
+} // TestTStripedPool_Open()
+
+func TestTStripedPool_Stats(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewStriped(testFactory, 0, 4, 2)
+	if nil != err {
+		t.Fatalf("NewStriped() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+	pool.Put(ctx, r)
+
+	got := pool.Stats()
+	if 1 != got.Gets {
+		t.Errorf("TStripedPool.Stats().Gets = `%v`, want `%v`", got.Gets, 1)
+	}
+	if 1 != got.Puts {
+		t.Errorf("TStripedPool.Stats().Puts = `%v`, want `%v`", got.Puts, 1)
+	}
+	if 1 != got.Misses {
+		t.Errorf("TStripedPool.Stats().Misses = `%v`, want `%v`", got.Misses, 1)
+	}
+} // TestTStripedPool_Stats()
+
+func TestTStripedPool_CloseContext(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewStriped(testFactory, 0, 2, 2)
+	if nil != err {
+		t.Fatalf("NewStriped() error = `%v`", err)
+	}
+
+	r, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		pool.Put(ctx, r)
+	}()
+
+	closeCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	if err := pool.CloseContext(closeCtx); nil != err {
+		t.Errorf("TStripedPool.CloseContext() error = `%v`, want `%v`", err, nil)
+	}
+} // TestTStripedPool_CloseContext()
+
+func TestTStripedPool_CloseContext_timesOut(t *testing.T) {
+	ctx := context.Background()
+	pool, err := NewStriped(testFactory, 0, 2, 2)
+	if nil != err {
+		t.Fatalf("NewStriped() error = `%v`", err)
+	}
+
+	if _, err := pool.Get(ctx); nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if err := pool.CloseContext(closeCtx); !errors.Is(err, ErrPoolDrainTimeout) {
+		t.Errorf("TStripedPool.CloseContext() error = `%v`, want it to wrap `%v`", err, ErrPoolDrainTimeout)
+	}
+} // TestTStripedPool_CloseContext_timesOut()
+
+/* _EoF_ */
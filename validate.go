@@ -0,0 +1,53 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+//lint:file-ignore ST1005 - Allow any error text
+
+import (
+	"io"
+)
+
+type (
+	// `TValidateFunc` is an optional function, settable via
+	// `Options.Validate`, that `Get()` calls on a pooled resource
+	// before handing it out. It should return `false` if the
+	// resource is no longer usable (e.g. a TCP/DB connection that
+	// was reset by the peer while sitting idle), in which case
+	// `Get()` closes it and tries the next one.
+	TValidateFunc func(io.Closer) bool
+
+	// `Poolable` is an optional contract a resource may implement
+	// to report its own health. When `Options.Validate` is `nil`,
+	// `Get()` falls back to calling `IsOpen()` on resources that
+	// implement this interface.
+	Poolable interface {
+		// `IsOpen` tells whether the resource is still usable.
+		IsOpen() bool
+	}
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `isValid` tells whether `aResource` may be handed out by `Get()`.
+//
+// It prefers `pool.validate` (if set), falling back to the `Poolable`
+// interface, and finally assumes the resource is valid if neither is
+// available.
+func (pool *TResPool) isValid(aResource io.Closer) bool {
+	if nil != pool.validate {
+		return pool.validate(aResource)
+	}
+	if p, ok := aResource.(Poolable); ok {
+		return p.IsOpen()
+	}
+
+	return true
+} // isValid()
+
+/* _EoF_ */
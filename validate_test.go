@@ -0,0 +1,92 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+type poolableCloser struct {
+	open bool
+}
+
+func (c *poolableCloser) Close() error {
+	return nil
+} // Close()
+
+func (c *poolableCloser) IsOpen() bool {
+	return c.open
+} // IsOpen()
+
+func TestTResPool_Get_Validate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	factory := func() (io.Closer, error) {
+		calls++
+		return &poolableCloser{open: true}, nil
+	}
+
+	pool, err := NewWithOptions(factory, 0, 2, Options{
+		Validate: func(r io.Closer) bool {
+			return r.(*poolableCloser).open
+		},
+	})
+	if nil != err {
+		t.Fatalf("NewWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	dead := &poolableCloser{open: false}
+	if err := pool.Put(ctx, dead); nil != err {
+		t.Fatalf("Put() error = `%v`", err)
+	}
+
+	// `Get()` must discard `dead` (fails validation) and fall back
+	// to the factory for a fresh resource.
+	got, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+	if r, ok := got.(*poolableCloser); !ok || !r.open {
+		t.Errorf("Get() = `%v`, want an open resource", got)
+	}
+	if 1 != calls {
+		t.Errorf("factory calls = `%v`, want `%v`", calls, 1)
+	}
+} // TestTResPool_Get_Validate()
+
+func TestTResPool_Get_Poolable(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool, err := New(func() (io.Closer, error) {
+		return &poolableCloser{open: true}, nil
+	}, 0, 2)
+	if nil != err {
+		t.Fatalf("New() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	dead := &poolableCloser{open: false}
+	pool.Put(ctx, dead)
+
+	got, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() error = `%v`", err)
+	}
+	if r, ok := got.(*poolableCloser); !ok || !r.open {
+		t.Errorf("Get() = `%v`, want an open resource", got)
+	}
+} // TestTResPool_Get_Poolable()
+
+/* _EoF_ */
@@ -0,0 +1,116 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+//lint:file-ignore ST1005 - Allow any error text
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// `Mode` selects how `Get()` behaves once `Options.MaxOpen` live
+// resources are already checked out and no idle one is available.
+type Mode int
+
+const (
+	// `ModeGrow` ignores `MaxOpen` and always creates a new resource
+	// via the factory function, exactly like a pool without a
+	// `MaxOpen` bound. This is the default (zero value).
+	ModeGrow Mode = iota
+
+	// `ModeWaitOrFail` parks the caller on the wait queue until a
+	// resource is `Put()` back or `aContext` is done (`ErrPoolDone`).
+	ModeWaitOrFail
+
+	// `ModeWaitOrTimeout` behaves like `ModeWaitOrFail` but also
+	// bounds the wait by `Options.WaitTimeout`, returning
+	// `ErrPoolTimeout` if that expires first.
+	ModeWaitOrTimeout
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `waitStats` wraps `wait()` with the bookkeeping for `Stats()`'s
+// `WaitCount`, `WaitDurationNs` and `Timeouts` fields.
+//
+// It's called with `pool.mtx` already held and always returns with the
+// mutex unlocked, exactly like `wait()`.
+func (pool *TResPool) waitStats(aContext context.Context) (io.Closer, error) {
+	pool.stats.waitCount.Add(1)
+	start := time.Now()
+
+	r, err := pool.wait(aContext)
+
+	pool.stats.waitDurationNs.Add(int64(time.Since(start)))
+	if ErrPoolTimeout == err {
+		pool.stats.timeouts.Add(1)
+	}
+
+	return r, err
+} // waitStats()
+
+// `wait` parks the caller on the pool's FIFO wait queue until a
+// resource becomes available, `aContext` is done, or (in
+// `ModeWaitOrTimeout`) `pool.waitTimeout` expires.
+//
+// It's called with `pool.mtx` already held and always returns with
+// the mutex unlocked.
+func (pool *TResPool) wait(aContext context.Context) (io.Closer, error) {
+	ch := make(chan io.Closer, 1)
+	pool.waiters = append(pool.waiters, ch)
+	pool.mtx.Unlock()
+
+	var timeoutC <-chan time.Time
+	if (ModeWaitOrTimeout == pool.mode) && (0 < pool.waitTimeout) {
+		timer := time.NewTimer(pool.waitTimeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+
+	select {
+	case r, ok := <-ch:
+		if !ok {
+			return nil, ErrPoolClosed
+		}
+		return r, nil
+
+	case <-aContext.Done():
+		pool.abandonWait(ch)
+		return nil, ErrPoolDone
+
+	case <-timeoutC:
+		pool.abandonWait(ch)
+		return nil, ErrPoolTimeout
+	} // select
+} // wait()
+
+// `abandonWait` removes `aCh` from the wait queue if it's still
+// there. If `Put()` already handed a resource to it in the meantime,
+// that resource is recovered and returned to the pool instead of
+// being leaked.
+func (pool *TResPool) abandonWait(aCh chan io.Closer) {
+	pool.mtx.Lock()
+	for i, w := range pool.waiters {
+		if w == aCh {
+			pool.waiters = append(pool.waiters[:i], pool.waiters[i+1:]...)
+			pool.mtx.Unlock()
+			return
+		}
+	}
+	pool.mtx.Unlock()
+
+	// We were already removed from the queue, meaning `Put()` is
+	// handing (or has handed) us a resource; don't let it leak.
+	if r, ok := <-aCh; ok {
+		pool.Put(context.Background(), r)
+	}
+} // abandonWait()
+
+/* _EoF_ */
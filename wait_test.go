@@ -0,0 +1,112 @@
+/*
+Copyright © 2023 M.Watermann, 10247 Berlin, Germany
+
+	    All rights reserved
+	EMail : <support@mwat.de>
+*/
+package respool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+func TestTResPool_Get_ModeGrow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// `ModeGrow` is the default: `MaxOpen` must not be enforced.
+	pool, err := NewWithOptions(testFactory, 0, 1, Options{MaxOpen: 1})
+	if nil != err {
+		t.Fatalf("NewWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	if _, err := pool.Get(ctx); nil != err {
+		t.Fatalf("Get() (1) error = `%v`", err)
+	}
+	if _, err := pool.Get(ctx); nil != err {
+		t.Fatalf("Get() (2) error = `%v`", err)
+	}
+	if got := pool.Open(); 2 != got {
+		t.Errorf("Open() = `%v`, want `%v`", got, 2)
+	}
+} // TestTResPool_Get_ModeGrow()
+
+func TestTResPool_Get_WaitOrFail(t *testing.T) {
+	pool, err := NewWithOptions(testFactory, 0, 1, Options{
+		MaxOpen: 1,
+		Mode:    ModeWaitOrFail,
+	})
+	if nil != err {
+		t.Fatalf("NewWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	r1, err := pool.Get(ctx)
+	if nil != err {
+		t.Fatalf("Get() (1) error = `%v`", err)
+	}
+
+	// The pool is now at `MaxOpen`; a second `Get()` must block
+	// until `r1` is `Put()` back.
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		r   interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		r, err := pool.Get(waitCtx)
+		done <- result{r, err}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Get() returned before a resource was available")
+	case <-time.After(20 * time.Millisecond):
+		// Still blocked, as expected.
+	}
+
+	if err := pool.Put(ctx, r1); nil != err {
+		t.Fatalf("Put() error = `%v`", err)
+	}
+
+	select {
+	case res := <-done:
+		if nil != res.err {
+			t.Errorf("Get() error = `%v`", res.err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Get() never unblocked after Put()")
+	}
+} // TestTResPool_Get_WaitOrFail()
+
+func TestTResPool_Get_WaitOrTimeout(t *testing.T) {
+	pool, err := NewWithOptions(testFactory, 0, 1, Options{
+		MaxOpen:     1,
+		Mode:        ModeWaitOrTimeout,
+		WaitTimeout: 10 * time.Millisecond,
+	})
+	if nil != err {
+		t.Fatalf("NewWithOptions() error = `%v`", err)
+	}
+	defer pool.Close()
+
+	ctx := context.Background()
+	if _, err := pool.Get(ctx); nil != err {
+		t.Fatalf("Get() (1) error = `%v`", err)
+	}
+
+	if _, err := pool.Get(ctx); ErrPoolTimeout != err {
+		t.Errorf("Get() (2) error = `%v`, want `%v`", err, ErrPoolTimeout)
+	}
+} // TestTResPool_Get_WaitOrTimeout()
+
+/* _EoF_ */